@@ -0,0 +1,44 @@
+package main
+
+import "fmt"
+
+// release is one entry in the version history shown by `goclitait changelog`.
+type release struct {
+	Version string   `json:"version"`
+	Notes   []string `json:"notes"`
+}
+
+// releases lists shipped versions, newest first. Keep the head in sync with version.
+var releases = []release{
+	{
+		Version: "0.1.0",
+		Notes: []string{
+			"Initial skeleton of the goclitait CLI",
+			"`version` command",
+			"`changelog` command for offline version history",
+			"`config` command and `<user config dir>/goclit/config.toml`",
+			"Global `--json` flag for machine-readable output",
+			"Global `--quiet` flag and documented exit codes",
+			"Command tree with global `--provider`, `--model` and `--config` flags and generated help",
+		},
+	},
+}
+
+func runChangelog(args []string) error {
+	if len(args) != 0 {
+		return newUsageError("changelog takes no arguments")
+	}
+	if opts.JSON {
+		return printJSON(releases)
+	}
+	for i, r := range releases {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("v%s\n", r.Version)
+		for _, n := range r.Notes {
+			fmt.Printf("  - %s\n", n)
+		}
+	}
+	return nil
+}
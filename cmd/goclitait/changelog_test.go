@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReleasesHeadMatchesVersion(t *testing.T) {
+	if len(releases) == 0 {
+		t.Fatal("releases is empty")
+	}
+	if releases[0].Version != version {
+		t.Errorf("releases[0].Version = %q, want %q (add a release entry for the new version)", releases[0].Version, version)
+	}
+}
+
+func TestRunChangelogText(t *testing.T) {
+	resetFlags(t)
+	out, err := captureStdout(t, func() error { return runChangelog(nil) })
+	if err != nil {
+		t.Fatalf("changelog: %v", err)
+	}
+	if !strings.HasPrefix(out, "v"+version+"\n") {
+		t.Errorf("output does not start with the current version:\n%s", out)
+	}
+	for _, n := range releases[0].Notes {
+		if !strings.Contains(out, "  - "+n+"\n") {
+			t.Errorf("output missing note %q", n)
+		}
+	}
+}
+
+func TestRunChangelogJSON(t *testing.T) {
+	resetFlags(t)
+	opts.JSON = true
+	out, err := captureStdout(t, func() error { return runChangelog(nil) })
+	if err != nil {
+		t.Fatalf("changelog --json: %v", err)
+	}
+	var got []release
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("decoding JSON output: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(got, releases) {
+		t.Errorf("decoded releases = %+v, want %+v", got, releases)
+	}
+	if !strings.Contains(out, `"version": "`+version+`"`) {
+		t.Errorf("JSON output does not use the version field name:\n%s", out)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// command is a node in the CLI command tree. Leaf commands have run set;
+// group commands dispatch to sub.
+type command struct {
+	name  string
+	args  string
+	short string
+	run   func(args []string) error
+	sub   []*command
+}
+
+// usageError is returned for malformed invocations; main prints the
+// command's usage after it.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+func newUsageError(format string, a ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, a...)}
+}
+
+var rootCmd = &command{
+	name: "goclitait",
+	sub: []*command{
+		{name: "version", short: "Print version information", run: runVersion},
+		{name: "changelog", short: "Show the version history", run: runChangelog},
+		configCmd,
+	},
+}
+
+func (c *command) lookup(name string) *command {
+	for _, s := range c.sub {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *command) execute(path, args []string) error {
+	if c.run != nil {
+		return c.run(args)
+	}
+	return subcommandError(path, args)
+}
+
+// subcommandError reports a missing or unknown subcommand of the group
+// command at path.
+func subcommandError(path, args []string) error {
+	what := "command"
+	if len(path) > 1 {
+		what = strings.Join(path[1:], " ") + " command"
+	}
+	if len(args) == 0 {
+		return newUsageError("missing %s", what)
+	}
+	return newUsageError("unknown %s %q", what, args[0])
+}
+
+// printUsage writes usage text for c, generated from the command tree and
+// the global flag table.
+func printUsage(w io.Writer, c *command, path []string) {
+	full := strings.Join(path, " ")
+	if c.run != nil {
+		fmt.Fprintf(w, "usage: %s [flags]", full)
+		if c.args != "" {
+			fmt.Fprintf(w, " %s", c.args)
+		}
+		fmt.Fprintln(w)
+		if c.short != "" {
+			fmt.Fprintf(w, "\n%s.\n", c.short)
+		}
+	} else {
+		fmt.Fprintf(w, "usage: %s [flags] <command> [args]\n\nCommands:\n", full)
+		for _, s := range c.sub {
+			name := s.name
+			if s.args != "" {
+				name += " " + s.args
+			}
+			fmt.Fprintf(w, "  %-22s %s\n", name, s.short)
+		}
+	}
+
+	fmt.Fprintln(w, "\nGlobal flags:")
+	for _, f := range globalFlags {
+		name := "--" + f.name
+		if f.short != "" {
+			name = "-" + f.short + ", " + name
+		}
+		if f.arg != "" {
+			name += " <" + f.arg + ">"
+		}
+		fmt.Fprintf(w, "  %-22s %s\n", name, f.help)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExecuteUsageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "missing command", args: nil, wantErr: "missing command"},
+		{name: "unknown command", args: []string{"frob"}, wantErr: `unknown command "frob"`},
+		{name: "missing subcommand", args: []string{"config"}, wantErr: "missing config command"},
+		{name: "unknown subcommand", args: []string{"config", "frob"}, wantErr: `unknown config command "frob"`},
+		{name: "version with arguments", args: []string{"version", "extra"}, wantErr: "version takes no arguments"},
+		{name: "changelog with arguments", args: []string{"changelog", "extra"}, wantErr: "changelog takes no arguments"},
+		{name: "config get without key", args: []string{"config", "get"}, wantErr: "config get takes exactly one key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			cmd, path, rest, err := parseCommandLine(rootCmd, tt.args)
+			if err != nil {
+				t.Fatalf("parseCommandLine: %v", err)
+			}
+			err = cmd.execute(path, rest)
+			var ue *usageError
+			if !errors.As(err, &ue) {
+				t.Fatalf("execute error = %v, want usage error", err)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("execute error = %q, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteRunsLeaf(t *testing.T) {
+	var got []string
+	leaf := &command{name: "leaf", run: func(args []string) error {
+		got = args
+		return nil
+	}}
+	root := &command{name: "root", sub: []*command{leaf}}
+
+	resetFlags(t)
+	cmd, path, rest, err := parseCommandLine(root, []string{"leaf", "a", "-b"})
+	if err != nil {
+		t.Fatalf("parseCommandLine: %v", err)
+	}
+	if err := cmd.execute(path, rest); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if want := []string{"a", "-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("leaf args = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/biodoia/goclitait/internal/config"
+)
+
+var configCmd = &command{
+	name:  "config",
+	short: "Read and write the config file",
+	sub: []*command{
+		{name: "list", short: "Print every effective config key and value", run: runConfigList},
+		{name: "get", args: "<key>", short: "Print one effective config value", run: runConfigGet},
+		{name: "set", args: "<key> <value>", short: "Set a config value and save", run: runConfigSet},
+	},
+}
+
+// configPath returns the config file selected by --config, or the default.
+func configPath() (string, error) {
+	if opts.ConfigPath != "" {
+		return opts.ConfigPath, nil
+	}
+	return config.Path()
+}
+
+// loadConfigFile reads the config file as stored on disk.
+func loadConfigFile() (*config.Config, string, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := config.LoadFile(path)
+	return cfg, path, err
+}
+
+// loadSettings returns the effective settings: the config file with the
+// --provider and --model overrides applied. The result must not be saved.
+func loadSettings() (*config.Config, error) {
+	cfg, _, err := loadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Provider != "" {
+		cfg.Provider = opts.Provider
+	}
+	if opts.Model != "" {
+		cfg.Model = opts.Model
+	}
+	return cfg, nil
+}
+
+func runConfigList(args []string) error {
+	if len(args) != 0 {
+		return newUsageError("config list takes no arguments")
+	}
+	cfg, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	values := make(map[string]string)
+	for _, k := range cfg.Keys() {
+		v, _ := cfg.Get(k)
+		if config.IsSecret(k) {
+			v = maskSecret(v)
+		}
+		if opts.JSON {
+			values[k] = v
+			continue
+		}
+		fmt.Printf("%s = %s\n", k, v)
+	}
+	if opts.JSON {
+		return printJSON(values)
+	}
+	return nil
+}
+
+func runConfigGet(args []string) error {
+	if len(args) != 1 {
+		return newUsageError("config get takes exactly one key")
+	}
+	cfg, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	v, err := cfg.Get(args[0])
+	if err != nil {
+		return err
+	}
+	if opts.JSON {
+		return printJSON(map[string]string{"key": args[0], "value": v})
+	}
+	fmt.Println(v)
+	return nil
+}
+
+func runConfigSet(args []string) error {
+	if len(args) != 2 {
+		return newUsageError("config set takes a key and a value")
+	}
+	cfg, path, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+	if err := cfg.Set(args[0], args[1]); err != nil {
+		return err
+	}
+	return cfg.SaveFile(path)
+}
+
+func maskSecret(s string) string {
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:4] + strings.Repeat("*", 8)
+}
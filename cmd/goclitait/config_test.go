@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goclitait/internal/config"
+)
+
+// captureStdout runs fn and returns what it wrote to os.Stdout.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	runErr := fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), runErr
+}
+
+// useTempConfig points --config at a fresh file for the duration of t.
+func useTempConfig(t *testing.T) string {
+	t.Helper()
+	resetFlags(t)
+	opts.ConfigPath = filepath.Join(t.TempDir(), "goclit", "config.toml")
+	return opts.ConfigPath
+}
+
+const testSecret = "sk-abcdefghijkl"
+
+func TestConfigSetSavesToConfigPath(t *testing.T) {
+	path := useTempConfig(t)
+	for _, args := range [][]string{
+		{"model", "gpt-4o"},
+		{"animation", "false"},
+		{"api_keys.openai", testSecret},
+	} {
+		if _, err := captureStdout(t, func() error { return runConfigSet(args) }); err != nil {
+			t.Fatalf("config set %q: %v", args, err)
+		}
+	}
+
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile(%s): %v", path, err)
+	}
+	if cfg.Model != "gpt-4o" || cfg.Animation || cfg.APIKeys["openai"] != testSecret {
+		t.Errorf("saved config = %+v", cfg)
+	}
+}
+
+func TestConfigSetErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "unknown key", args: []string{"modle", "x"}, want: `unknown config key "modle"`},
+		{name: "bad boolean", args: []string{"animation", "maybe"}, want: `animation: "maybe" is not a boolean`},
+		{name: "bad provider name", args: []string{"api_keys.a=b", "x"}, want: `invalid provider name "a=b"`},
+		{name: "missing value", args: []string{"model"}, want: "config set takes a key and a value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := useTempConfig(t)
+			_, err := captureStdout(t, func() error { return runConfigSet(tt.args) })
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("error = %v, want prefix %q", err, tt.want)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("config file written after failed set (stat err %v)", err)
+			}
+		})
+	}
+}
+
+func TestConfigListMasksAPIKeys(t *testing.T) {
+	useTempConfig(t)
+	if err := runConfigSet([]string{"api_keys.openai", testSecret}); err != nil {
+		t.Fatal(err)
+	}
+	masked := maskSecret(testSecret)
+
+	out, err := captureStdout(t, func() error { return runConfigList(nil) })
+	if err != nil {
+		t.Fatalf("config list: %v", err)
+	}
+	if strings.Contains(out, testSecret) {
+		t.Errorf("text output leaks the API key:\n%s", out)
+	}
+	if !strings.Contains(out, "api_keys.openai = "+masked+"\n") {
+		t.Errorf("text output missing masked key:\n%s", out)
+	}
+
+	opts.JSON = true
+	out, err = captureStdout(t, func() error { return runConfigList(nil) })
+	if err != nil {
+		t.Fatalf("config list --json: %v", err)
+	}
+	if strings.Contains(out, testSecret) {
+		t.Errorf("JSON output leaks the API key:\n%s", out)
+	}
+	var values map[string]string
+	if err := json.Unmarshal([]byte(out), &values); err != nil {
+		t.Fatalf("decoding JSON output: %v\n%s", err, out)
+	}
+	if values["api_keys.openai"] != masked {
+		t.Errorf("api_keys.openai = %q, want %q", values["api_keys.openai"], masked)
+	}
+}
+
+func TestConfigGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		json    bool
+		want    string
+		wantErr string
+	}{
+		{name: "stored value", args: []string{"model"}, want: "gpt-4o\n"},
+		{name: "default value", args: []string{"theme"}, want: "dark\n"},
+		{name: "api key is not masked", args: []string{"api_keys.openai"}, want: testSecret + "\n"},
+		{name: "json", args: []string{"model"}, json: true, want: "{\n  \"key\": \"model\",\n  \"value\": \"gpt-4o\"\n}\n"},
+		{name: "unknown key", args: []string{"modle"}, wantErr: `unknown config key "modle"`},
+		{name: "no key", args: nil, wantErr: "config get takes exactly one key"},
+		{name: "two keys", args: []string{"model", "theme"}, wantErr: "config get takes exactly one key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempConfig(t)
+			if err := runConfigSet([]string{"model", "gpt-4o"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := runConfigSet([]string{"api_keys.openai", testSecret}); err != nil {
+				t.Fatal(err)
+			}
+			opts.JSON = tt.json
+
+			out, err := captureStdout(t, func() error { return runConfigGet(tt.args) })
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("config get: %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("output = %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "", want: ""},
+		{in: "abc", want: "***"},
+		{in: "12345678", want: "********"},
+		{in: "sk-123456789", want: "sk-1********"},
+		{in: testSecret, want: "sk-a********"},
+	}
+	for _, tt := range tests {
+		if got := maskSecret(tt.in); got != tt.want {
+			t.Errorf("maskSecret(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestProviderModelOverrides(t *testing.T) {
+	path := useTempConfig(t)
+	if err := runConfigSet([]string{"provider", "openai"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runConfigSet([]string{"model", "gpt-4o"}); err != nil {
+		t.Fatal(err)
+	}
+	opts.Provider = "ollama"
+	opts.Model = "llama3"
+
+	for key, want := range map[string]string{"provider": "ollama\n", "model": "llama3\n"} {
+		out, err := captureStdout(t, func() error { return runConfigGet([]string{key}) })
+		if err != nil {
+			t.Fatalf("config get %s: %v", key, err)
+		}
+		if out != want {
+			t.Errorf("config get %s = %q, want %q", key, out, want)
+		}
+	}
+
+	out, err := captureStdout(t, func() error { return runConfigList(nil) })
+	if err != nil {
+		t.Fatalf("config list: %v", err)
+	}
+	for _, line := range []string{"provider = ollama\n", "model = llama3\n"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("config list missing %q:\n%s", line, out)
+		}
+	}
+
+	if err := runConfigSet([]string{"theme", "light"}); err != nil {
+		t.Fatalf("config set theme: %v", err)
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Provider != "openai" || cfg.Model != "gpt-4o" {
+		t.Errorf("saved provider/model = %q/%q, want openai/gpt-4o: overrides leaked into the file", cfg.Provider, cfg.Model)
+	}
+	if cfg.Theme != "light" {
+		t.Errorf("saved theme = %q, want light", cfg.Theme)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+// Process exit codes. These are part of the CLI contract for scripts and
+// Makefiles, so existing values must not change. Codes 2-4 are reserved for
+// the provider, budget and cancellation paths and are not produced yet.
+const (
+	exitError          = 1 // usage errors and anything not covered below
+	exitProviderError  = 2
+	exitBudgetExceeded = 3
+	exitCancelled      = 4
+)
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// globalOptions holds flags accepted by every command.
+type globalOptions struct {
+	Provider   string
+	Model      string
+	ConfigPath string
+	JSON       bool
+	Quiet      bool
+}
+
+var opts globalOptions
+
+// globalFlag describes one global flag. Exactly one of str or boolean is set.
+type globalFlag struct {
+	name    string
+	short   string
+	arg     string
+	help    string
+	str     *string
+	boolean *bool
+}
+
+var globalFlags = []globalFlag{
+	{name: "provider", arg: "name", help: "provider to use, overriding config", str: &opts.Provider},
+	{name: "model", arg: "name", help: "model to use, overriding config", str: &opts.Model},
+	{name: "config", arg: "path", help: "config file (default <user config dir>/goclit/config.toml)", str: &opts.ConfigPath},
+	{name: "json", help: "print machine-readable JSON", boolean: &opts.JSON},
+	{name: "quiet", short: "q", help: "suppress banners and decorative output", boolean: &opts.Quiet},
+	{name: "help", short: "h", help: "show usage", boolean: &helpRequested},
+}
+
+var helpRequested bool
+
+// parseCommandLine resolves the command named in args and applies global
+// flags. Flags may appear before, between and after command names, up to
+// the leaf command's first positional argument; from there on every
+// argument is positional. "--" ends flag parsing but command names after it
+// are still resolved. The word "help" in place of a subcommand name
+// requests usage, like -h. On error the command resolved so far is still
+// returned so its usage can be printed.
+func parseCommandLine(root *command, args []string) (cmd *command, path, rest []string, err error) {
+	cmd, path = root, []string{root.name}
+	flagsDone := false
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !flagsDone && a == "--" {
+			flagsDone = true
+			continue
+		}
+		if !flagsDone && strings.HasPrefix(a, "-") && a != "-" {
+			n, err := applyFlag(args[i:])
+			if err != nil {
+				return cmd, path, nil, err
+			}
+			i += n
+			continue
+		}
+		if cmd.run != nil {
+			return cmd, path, append(rest, args[i:]...), nil
+		}
+		if len(rest) == 0 {
+			if a == "help" {
+				helpRequested = true
+				continue
+			}
+			if next := cmd.lookup(a); next != nil {
+				cmd, path = next, append(path, a)
+				continue
+			}
+		}
+		rest = append(rest, a)
+	}
+	return cmd, path, rest, nil
+}
+
+// applyFlag applies the flag at args[0] and returns how many following
+// arguments it consumed as its value.
+func applyFlag(args []string) (int, error) {
+	f, value, hasValue := lookupFlag(args[0])
+	if f == nil {
+		return 0, fmt.Errorf("unknown flag %s (put -- before arguments that start with \"-\")", args[0])
+	}
+	if f.boolean != nil {
+		b := true
+		if hasValue {
+			var err error
+			if b, err = strconv.ParseBool(value); err != nil {
+				return 0, fmt.Errorf("flag --%s: %q is not a boolean", f.name, value)
+			}
+		}
+		*f.boolean = b
+		return 0, nil
+	}
+	if hasValue {
+		*f.str = value
+		return 0, nil
+	}
+	if len(args) < 2 {
+		return 0, fmt.Errorf("flag --%s needs a value", f.name)
+	}
+	*f.str = args[1]
+	return 1, nil
+}
+
+// lookupFlag resolves a "--name[=value]" or "-short[=value]" argument.
+// It returns nil for any other spelling, such as "-name" or "---name".
+func lookupFlag(arg string) (f *globalFlag, value string, hasValue bool) {
+	var name string
+	long := false
+	if rest, ok := strings.CutPrefix(arg, "--"); ok {
+		name, long = rest, true
+	} else {
+		name = strings.TrimPrefix(arg, "-")
+	}
+	name, value, hasValue = strings.Cut(name, "=")
+	if name == "" || strings.HasPrefix(name, "-") {
+		return nil, "", false
+	}
+	for i := range globalFlags {
+		g := &globalFlags[i]
+		if (long && g.name == name) || (!long && g.short != "" && g.short == name) {
+			return g, value, hasValue
+		}
+	}
+	return nil, "", false
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func resetFlags(t *testing.T) {
+	t.Helper()
+	opts = globalOptions{}
+	helpRequested = false
+	t.Cleanup(func() {
+		opts = globalOptions{}
+		helpRequested = false
+	})
+}
+
+func TestParseCommandLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantPath []string
+		wantRest []string
+		wantOpts globalOptions
+		wantHelp bool
+	}{
+		{
+			name:     "no arguments",
+			args:     nil,
+			wantPath: []string{"goclitait"},
+		},
+		{
+			name:     "long flag with equals",
+			args:     []string{"--model=gpt-4o", "version"},
+			wantPath: []string{"goclitait", "version"},
+			wantOpts: globalOptions{Model: "gpt-4o"},
+		},
+		{
+			name:     "long flag with separate value",
+			args:     []string{"config", "--provider", "ollama", "list"},
+			wantPath: []string{"goclitait", "config", "list"},
+			wantOpts: globalOptions{Provider: "ollama"},
+		},
+		{
+			name:     "boolean flags",
+			args:     []string{"--json", "version", "-q"},
+			wantPath: []string{"goclitait", "version"},
+			wantOpts: globalOptions{JSON: true, Quiet: true},
+		},
+		{
+			name:     "explicit boolean value",
+			args:     []string{"--quiet=false", "version"},
+			wantPath: []string{"goclitait", "version"},
+		},
+		{
+			name:     "short help",
+			args:     []string{"config", "set", "-h"},
+			wantPath: []string{"goclitait", "config", "set"},
+			wantHelp: true,
+		},
+		{
+			name:     "flags stop at positional arguments",
+			args:     []string{"config", "set", "--json", "api_keys.openai", "-abc", "--quiet"},
+			wantPath: []string{"goclitait", "config", "set"},
+			wantRest: []string{"api_keys.openai", "-abc", "--quiet"},
+			wantOpts: globalOptions{JSON: true},
+		},
+		{
+			name:     "double dash passthrough",
+			args:     []string{"config", "set", "--", "-k", "--json"},
+			wantPath: []string{"goclitait", "config", "set"},
+			wantRest: []string{"-k", "--json"},
+		},
+		{
+			name:     "command names after double dash",
+			args:     []string{"--json", "--", "config", "get", "-k"},
+			wantPath: []string{"goclitait", "config", "get"},
+			wantRest: []string{"-k"},
+			wantOpts: globalOptions{JSON: true},
+		},
+		{
+			name:     "double dash before unknown command",
+			args:     []string{"--", "-x"},
+			wantPath: []string{"goclitait"},
+			wantRest: []string{"-x"},
+		},
+		{
+			name:     "help word at the root",
+			args:     []string{"help", "config", "set"},
+			wantPath: []string{"goclitait", "config", "set"},
+			wantHelp: true,
+		},
+		{
+			name:     "help word in a command group",
+			args:     []string{"config", "help", "get"},
+			wantPath: []string{"goclitait", "config", "get"},
+			wantHelp: true,
+		},
+		{
+			name:     "help word for an unknown command",
+			args:     []string{"config", "help", "frob"},
+			wantPath: []string{"goclitait", "config"},
+			wantRest: []string{"frob"},
+			wantHelp: true,
+		},
+		{
+			name:     "help word is positional for a leaf",
+			args:     []string{"config", "get", "help"},
+			wantPath: []string{"goclitait", "config", "get"},
+			wantRest: []string{"help"},
+		},
+		{
+			name:     "single dash is positional",
+			args:     []string{"config", "get", "-"},
+			wantPath: []string{"goclitait", "config", "get"},
+			wantRest: []string{"-"},
+		},
+		{
+			name:     "unknown subcommand keeps parsing flags",
+			args:     []string{"config", "frob", "--json"},
+			wantPath: []string{"goclitait", "config"},
+			wantRest: []string{"frob"},
+			wantOpts: globalOptions{JSON: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			_, path, rest, err := parseCommandLine(rootCmd, tt.args)
+			if err != nil {
+				t.Fatalf("parseCommandLine: %v", err)
+			}
+			if !reflect.DeepEqual(path, tt.wantPath) {
+				t.Errorf("path = %q, want %q", path, tt.wantPath)
+			}
+			if len(rest) != 0 || len(tt.wantRest) != 0 {
+				if !reflect.DeepEqual(rest, tt.wantRest) {
+					t.Errorf("rest = %q, want %q", rest, tt.wantRest)
+				}
+			}
+			if opts != tt.wantOpts {
+				t.Errorf("opts = %+v, want %+v", opts, tt.wantOpts)
+			}
+			if helpRequested != tt.wantHelp {
+				t.Errorf("helpRequested = %t, want %t", helpRequested, tt.wantHelp)
+			}
+		})
+	}
+}
+
+func TestParseCommandLineErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "missing value", args: []string{"version", "--model"}, wantErr: "flag --model needs a value"},
+		{name: "bad boolean", args: []string{"--json=maybe"}, wantErr: `flag --json: "maybe" is not a boolean`},
+		{name: "unknown long flag", args: []string{"--bogus"}, wantErr: "unknown flag --bogus"},
+		{name: "long name with one dash", args: []string{"-json"}, wantErr: "unknown flag -json"},
+		{name: "three dashes", args: []string{"---json"}, wantErr: "unknown flag ---json"},
+		{name: "short name with two dashes", args: []string{"--q"}, wantErr: "unknown flag --q"},
+		{name: "dash value before positional", args: []string{"config", "set", "-abc", "x"}, wantErr: "unknown flag -abc (put -- before"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			_, _, _, err := parseCommandLine(rootCmd, tt.args)
+			if err == nil {
+				t.Fatal("parseCommandLine succeeded, want error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want prefix %q", err, tt.wantErr)
+			}
+		})
+	}
+}
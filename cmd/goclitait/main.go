@@ -3,6 +3,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 )
@@ -10,12 +11,59 @@ import (
 const version = "0.1.0"
 
 func main() {
-	if len(os.Args) > 1 && os.Args[1] == "version" {
-		fmt.Printf("goclitait v%s\n", version)
-		fmt.Println("The Dream CLI - Synthesis of 65 coding agents")
+	cmd, path, rest, err := parseCommandLine(rootCmd, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "goclitait:", err)
+		printUsage(os.Stderr, cmd, path)
+		os.Exit(exitError)
+	}
+
+	if helpRequested {
+		if cmd.run == nil && len(rest) > 0 {
+			fmt.Fprintln(os.Stderr, "goclitait:", subcommandError(path, rest))
+			printUsage(os.Stderr, cmd, path)
+			os.Exit(exitError)
+		}
+		printUsage(os.Stdout, cmd, path)
 		return
 	}
 
+	// The banner is text, so with --json a bare invocation falls through to
+	// the "missing command" usage error instead.
+	if cmd == rootCmd && len(rest) == 0 && !opts.JSON {
+		printBanner()
+		return
+	}
+
+	if err := cmd.execute(path, rest); err != nil {
+		fmt.Fprintln(os.Stderr, "goclitait:", err)
+		var ue *usageError
+		if errors.As(err, &ue) {
+			printUsage(os.Stderr, cmd, path)
+		}
+		os.Exit(exitError)
+	}
+}
+
+func printBanner() {
+	if opts.Quiet {
+		return
+	}
 	fmt.Println("🚀 goclitait - The Dream CLI")
 	fmt.Println("Coming soon: RepoMap + MCP + Memory + Multi-Agent")
+	fmt.Println("Run 'goclitait help' for a list of commands.")
+}
+
+func runVersion(args []string) error {
+	if len(args) != 0 {
+		return newUsageError("version takes no arguments")
+	}
+	if opts.JSON {
+		return printJSON(map[string]string{"version": version})
+	}
+	fmt.Printf("goclitait v%s\n", version)
+	if !opts.Quiet {
+		fmt.Println("The Dream CLI - Synthesis of 65 coding agents")
+	}
+	return nil
 }
@@ -0,0 +1,429 @@
+// Package config loads and saves the goclitait user configuration file.
+//
+// The file lives at <user config dir>/goclit/config.toml and uses a small
+// TOML subset: top-level "key = value" pairs plus an [api_keys] table.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config holds the persisted user settings.
+type Config struct {
+	Provider  string
+	Model     string
+	Theme     string
+	Animation bool
+	APIKeys   map[string]string
+}
+
+const apiKeysPrefix = "api_keys."
+
+// Default returns the settings used when no config file exists.
+func Default() *Config {
+	return &Config{
+		Theme:     "dark",
+		Animation: true,
+		APIKeys:   map[string]string{},
+	}
+}
+
+// Path returns the location of the config file.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "goclit", "config.toml"), nil
+}
+
+// LoadFile reads the config at path, returning defaults if it does not exist.
+func LoadFile(path string) (*Config, error) {
+	cfg := Default()
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	section := ""
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			header, _, _ := strings.Cut(line, "#")
+			header = strings.TrimSpace(header)
+			if !strings.HasSuffix(header, "]") {
+				return nil, fmt.Errorf("%s:%d: unterminated table header", path, n)
+			}
+			section = strings.TrimSpace(header[1 : len(header)-1])
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, n)
+		}
+		key := strings.TrimSpace(k)
+		if section != "" {
+			key = section + "." + key
+		}
+		val, err := parseValue(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		if err := cfg.Set(key, val); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SaveFile writes the config to path, creating its directory if needed.
+// The file is private to the user because it may contain API keys.
+//
+// An existing file is updated in place: lines whose value is unchanged are
+// kept exactly as written, changed values keep their trailing comment,
+// comments and blank lines are preserved, and removed API keys are dropped.
+func (c *Config) SaveFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	old, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return writePrivate(path, c.render(old))
+}
+
+// render merges c into the previous file contents old.
+func (c *Config) render(old []byte) []byte {
+	pending := make(map[string]bool)
+	for _, k := range c.Keys() {
+		pending[k] = true
+	}
+
+	var out []string
+	section := ""
+	insertAt := 0 // where keys missing from the current section are added
+	flush := func() {
+		var add []string
+		for _, k := range c.Keys() {
+			if !pending[k] || sectionOf(k) != section {
+				continue
+			}
+			name := k
+			if section != "" {
+				name = strings.TrimPrefix(k, section+".")
+			}
+			add = append(add, name+" = "+c.encode(k))
+			delete(pending, k)
+		}
+		out = slices.Insert(out, insertAt, add...)
+	}
+
+	var lines []string
+	if len(old) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(old), "\n"), "\n")
+	}
+	for _, raw := range lines {
+		raw = strings.TrimSuffix(raw, "\r")
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+			out = append(out, raw)
+		case strings.HasPrefix(line, "#"):
+			out = append(out, raw)
+			insertAt = len(out)
+		case strings.HasPrefix(line, "["):
+			flush()
+			header, _, _ := strings.Cut(line, "#")
+			header = strings.TrimSpace(header)
+			section = strings.TrimSpace(strings.TrimSuffix(header[1:], "]"))
+			out = append(out, raw)
+			insertAt = len(out)
+		default:
+			k, v, ok := strings.Cut(line, "=")
+			if !ok {
+				out = append(out, raw)
+				continue
+			}
+			name := strings.TrimSpace(k)
+			key := name
+			if section != "" {
+				key = section + "." + name
+			}
+			if !pending[key] {
+				// Removed API key, or a repeated key already written.
+				continue
+			}
+			delete(pending, key)
+			current, _ := c.Get(key)
+			if prev, err := parseValue(strings.TrimSpace(v)); err == nil && prev == current {
+				out = append(out, raw)
+			} else {
+				indent := raw[:len(raw)-len(strings.TrimLeft(raw, " \t"))]
+				updated := indent + name + " = " + c.encode(key)
+				if _, comment, err := splitComment(strings.TrimSpace(v)); err == nil && comment != "" {
+					updated += " " + comment
+				}
+				out = append(out, updated)
+			}
+			insertAt = len(out)
+		}
+	}
+	flush()
+
+	if len(pending) > 0 {
+		// API keys, and no [api_keys] table in the file yet.
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" {
+			out = append(out, "")
+		}
+		out = append(out, "[api_keys]")
+		section, insertAt = "api_keys", len(out)
+		flush()
+	}
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// encode returns the TOML form of the value stored under key.
+func (c *Config) encode(key string) string {
+	v, _ := c.Get(key)
+	if key == "animation" {
+		return v
+	}
+	return quote(v)
+}
+
+func sectionOf(key string) string {
+	if strings.HasPrefix(key, apiKeysPrefix) {
+		return strings.TrimSuffix(apiKeysPrefix, ".")
+	}
+	return ""
+}
+
+// writePrivate replaces path with data through a temporary file in the same
+// directory, so the result is always mode 0600 even if path already existed
+// with a wider mode, and readers never see a partial file.
+func writePrivate(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Get returns the value of a config key such as "model" or "api_keys.openai".
+func (c *Config) Get(key string) (string, error) {
+	switch key {
+	case "provider":
+		return c.Provider, nil
+	case "model":
+		return c.Model, nil
+	case "theme":
+		return c.Theme, nil
+	case "animation":
+		return strconv.FormatBool(c.Animation), nil
+	}
+	name, err := apiKeyName(key)
+	if err != nil {
+		return "", err
+	}
+	return c.APIKeys[name], nil
+}
+
+// Set assigns a config key from its string form.
+func (c *Config) Set(key, value string) error {
+	switch key {
+	case "provider":
+		c.Provider = value
+	case "model":
+		c.Model = value
+	case "theme":
+		c.Theme = value
+	case "animation":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("animation: %q is not a boolean", value)
+		}
+		c.Animation = b
+	default:
+		name, err := apiKeyName(key)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			delete(c.APIKeys, name)
+		} else {
+			c.APIKeys[name] = value
+		}
+	}
+	return nil
+}
+
+// Keys returns every key that currently has a value, in display order.
+func (c *Config) Keys() []string {
+	keys := []string{"provider", "model", "theme", "animation"}
+	for _, name := range sortedKeys(c.APIKeys) {
+		keys = append(keys, apiKeysPrefix+name)
+	}
+	return keys
+}
+
+// apiKeyName returns the provider name in an "api_keys.<name>" key. Names
+// must be bare TOML keys so that SaveFile always writes a loadable file.
+func apiKeyName(key string) (string, error) {
+	name, ok := strings.CutPrefix(key, apiKeysPrefix)
+	if !ok {
+		return "", fmt.Errorf("unknown config key %q", key)
+	}
+	if !isBareKey(name) {
+		return "", fmt.Errorf("invalid provider name %q in %q: use letters, digits, '_' or '-'", name, key)
+	}
+	return name, nil
+}
+
+func isBareKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// IsSecret reports whether key holds a credential that should be masked.
+func IsSecret(key string) bool {
+	return strings.HasPrefix(key, apiKeysPrefix)
+}
+
+// parseValue decodes a quoted string or boolean, ignoring a trailing
+// "# comment" outside the quotes.
+func parseValue(s string) (string, error) {
+	s, _, err := splitComment(s)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(s, `"`) {
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", s)
+		}
+		return v, nil
+	}
+	if s == "true" || s == "false" {
+		return s, nil
+	}
+	return "", fmt.Errorf("unsupported value %s", s)
+}
+
+// splitComment separates a raw value from a trailing "# comment", which is
+// returned with its "#". A "#" inside a quoted string is not a comment.
+func splitComment(s string) (value, comment string, err error) {
+	if strings.HasPrefix(s, `"`) {
+		end := closingQuote(s)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string %s", s)
+		}
+		rest := strings.TrimSpace(s[end+1:])
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", "", fmt.Errorf("unexpected %s after string", rest)
+		}
+		return s[:end+1], rest, nil
+	}
+	value, comment, found := strings.Cut(s, "#")
+	if found {
+		comment = "#" + comment
+	}
+	return strings.TrimSpace(value), strings.TrimSpace(comment), nil
+}
+
+// quote returns s as a TOML basic string. Unlike strconv.Quote it never
+// emits Go-only escapes such as \x01, which TOML parsers reject.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// closingQuote returns the index of the quote ending the string that
+// starts at s[0], skipping backslash escapes, or -1 if there is none.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
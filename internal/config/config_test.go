@@ -0,0 +1,365 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileTrailingComments(t *testing.T) {
+	path := writeFile(t, `model = "x" # mine
+theme = "a # b" # quoted hash is not a comment
+animation = false # off
+[api_keys] # credentials
+openai = "sk-\"q\"" # escaped quotes
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.Model != "x" {
+		t.Errorf("Model = %q, want %q", cfg.Model, "x")
+	}
+	if cfg.Theme != "a # b" {
+		t.Errorf("Theme = %q, want %q", cfg.Theme, "a # b")
+	}
+	if cfg.Animation {
+		t.Error("Animation = true, want false")
+	}
+	if got := cfg.APIKeys["openai"]; got != `sk-"q"` {
+		t.Errorf("APIKeys[openai] = %q, want %q", got, `sk-"q"`)
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "defaults",
+			cfg:  Default(),
+		},
+		{
+			name: "quotes and backslashes",
+			cfg: &Config{
+				Provider:  `say "hi"`,
+				Model:     `C:\models\llama`,
+				Theme:     `a\"b # c`,
+				Animation: false,
+				APIKeys:   map[string]string{},
+			},
+		},
+		{
+			name: "several api keys",
+			cfg: &Config{
+				Provider:  "openai",
+				Model:     "gpt-4o",
+				Theme:     "light",
+				Animation: true,
+				APIKeys: map[string]string{
+					"openai":    "sk-1",
+					"anthropic": `sk-"2"`,
+					"gemini":    `k\3`,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "goclit", "config.toml")
+			if err := tt.cfg.SaveFile(path); err != nil {
+				t.Fatalf("SaveFile: %v", err)
+			}
+			got, err := LoadFile(path)
+			if err != nil {
+				t.Fatalf("LoadFile: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.cfg) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.cfg)
+			}
+		})
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "bad line",
+			content: "model = \"x\"\nnot a setting\n",
+			want:    ":2: expected key = value",
+		},
+		{
+			name:    "unknown key",
+			content: "# comment\n\nmodle = \"x\"\n",
+			want:    `:3: unknown config key "modle"`,
+		},
+		{
+			name:    "unknown table",
+			content: "[keys]\nopenai = \"x\"\n",
+			want:    `:2: unknown config key "keys.openai"`,
+		},
+		{
+			name:    "non-boolean animation",
+			content: "animation = \"yes\"\n",
+			want:    `:1: animation: "yes" is not a boolean`,
+		},
+		{
+			name:    "unterminated string",
+			content: "model = \"x\n",
+			want:    ":1: unterminated string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, tt.content)
+			_, err := LoadFile(path)
+			if err == nil {
+				t.Fatal("LoadFile succeeded, want error")
+			}
+			if want := path + tt.want; !strings.HasPrefix(err.Error(), want) {
+				t.Errorf("error = %q, want prefix %q", err, want)
+			}
+		})
+	}
+}
+
+func TestLoadFileMissingReturnsDefault(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("LoadFile = %+v, want %+v", cfg, Default())
+	}
+}
+
+func TestSetRejectsInvalidAPIKeyNames(t *testing.T) {
+	names := []string{"", "a=b", "a b", "a#b", "a]b", "a\nb", "a.b", `a"b`}
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			cfg := Default()
+			key := "api_keys." + name
+			if err := cfg.Set(key, "x"); err == nil {
+				t.Fatalf("Set(%q) succeeded, want error", key)
+			}
+			if _, err := cfg.Get(key); err == nil {
+				t.Errorf("Get(%q) succeeded, want error", key)
+			}
+
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if err := cfg.SaveFile(path); err != nil {
+				t.Fatalf("SaveFile: %v", err)
+			}
+			if _, err := LoadFile(path); err != nil {
+				t.Errorf("LoadFile after rejected Set: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetAcceptsBareAPIKeyNames(t *testing.T) {
+	cfg := Default()
+	for _, name := range []string{"openai", "google-vertex", "hf_2"} {
+		if err := cfg.Set("api_keys."+name, "k"); err != nil {
+			t.Errorf("Set(api_keys.%s): %v", name, err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := cfg.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("round trip = %+v, want %+v", got, cfg)
+	}
+}
+
+func TestSaveFileMakesExistingFilePrivate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("model = \"x\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if err := cfg.Set("api_keys.openai", "sk-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("mode = %v, want %v", mode, os.FileMode(0o600))
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries after save, want only config.toml", len(entries))
+	}
+}
+
+func TestSaveFilePreservesHandEdits(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		set    [][2]string
+		want   string
+	}{
+		{
+			name: "unrelated set keeps comments and formatting",
+			before: `# my settings
+provider="openai"   # default
+model = "m" # my model
+theme = "dark"
+animation = true
+`,
+			set: [][2]string{{"theme", "light"}},
+			want: `# my settings
+provider="openai"   # default
+model = "m" # my model
+theme = "light"
+animation = true
+`,
+		},
+		{
+			name:   "changed value keeps its trailing comment",
+			before: "  model = \"m\" # my model\n",
+			set:    [][2]string{{"model", "n"}},
+			want: `  model = "n" # my model
+provider = ""
+theme = "dark"
+animation = true
+`,
+		},
+		{
+			name: "new api key is added to the existing table",
+			before: `model = "m"
+
+[api_keys] # credentials
+# work account
+openai = "sk-1" # rotated monthly
+`,
+			set: [][2]string{{"api_keys.anthropic", "sk-2"}},
+			want: `model = "m"
+provider = ""
+theme = "dark"
+animation = true
+
+[api_keys] # credentials
+# work account
+openai = "sk-1" # rotated monthly
+anthropic = "sk-2"
+`,
+		},
+		{
+			name: "removed api key is dropped",
+			before: `[api_keys]
+openai = "sk-1"
+gemini = "g" # keep me
+`,
+			set: [][2]string{{"api_keys.openai", ""}},
+			want: `provider = ""
+model = ""
+theme = "dark"
+animation = true
+[api_keys]
+gemini = "g" # keep me
+`,
+		},
+		{
+			name:   "api keys table is created when missing",
+			before: "model = \"m\" # mine\n",
+			set:    [][2]string{{"api_keys.openai", "sk-1"}},
+			want: `model = "m" # mine
+provider = ""
+theme = "dark"
+animation = true
+
+[api_keys]
+openai = "sk-1"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, tt.before)
+			cfg, err := LoadFile(path)
+			if err != nil {
+				t.Fatalf("LoadFile: %v", err)
+			}
+			for _, kv := range tt.set {
+				if err := cfg.Set(kv[0], kv[1]); err != nil {
+					t.Fatalf("Set(%q): %v", kv[0], err)
+				}
+			}
+			if err := cfg.SaveFile(path); err != nil {
+				t.Fatalf("SaveFile: %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("saved file:\n%s\nwant:\n%s", got, tt.want)
+			}
+			reloaded, err := LoadFile(path)
+			if err != nil {
+				t.Fatalf("LoadFile after save: %v", err)
+			}
+			if !reflect.DeepEqual(reloaded, cfg) {
+				t.Errorf("reloaded = %+v, want %+v", reloaded, cfg)
+			}
+		})
+	}
+}
+
+func TestQuoteUsesTOMLEscapes(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "plain", want: `"plain"`},
+		{in: `a"b\c`, want: `"a\"b\\c"`},
+		{in: "tab\tnl\ncr\r", want: `"tab\tnl\ncr\r"`},
+		{in: "a\x01b\x7f", want: `"a\u0001b\u007F"`},
+		{in: "héllo ✓", want: `"héllo ✓"`},
+	}
+	for _, tt := range tests {
+		if got := quote(tt.in); got != tt.want {
+			t.Errorf("quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		if back, err := parseValue(quote(tt.in)); err != nil || back != tt.in {
+			t.Errorf("parseValue(quote(%q)) = %q, %v", tt.in, back, err)
+		}
+	}
+}